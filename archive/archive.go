@@ -0,0 +1,266 @@
+// Package archive 提供 zip 与 tar.gz 格式的打包与解包工具函数。
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ahui2016/goutil"
+)
+
+// ZipDir 把 srcDir 文件夹的全部内容打包为 destZip, 保留相对路径与文件权限。
+// 与 ArchiveFiles 不同, 这里的 zip 条目名是相对于 srcDir 的路径, 而不是 base name,
+// 因此不同子文件夹下的同名文件不会互相覆盖。
+func ZipDir(srcDir, destZip string) error {
+	files, err := walkFiles(srcDir)
+	if err != nil {
+		return err
+	}
+
+	_, out, err := goutil.CreateReturnFile(destZip, strings.NewReader(""))
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	w := zip.NewWriter(out)
+	defer w.Close()
+
+	for _, file := range files {
+		rel, err := filepath.Rel(srcDir, file)
+		if err != nil {
+			return err
+		}
+		if err := addFileToZip(w, file, rel); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ArchiveFiles 把 files 中列出的文件打包为 destZip, 文件在 zip 中的路径使用其 base name,
+// 适用于从不同位置挑选文件、没有共同根目录的场景。
+func ArchiveFiles(files []string, destZip string) error {
+	_, out, err := goutil.CreateReturnFile(destZip, strings.NewReader(""))
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	w := zip.NewWriter(out)
+	defer w.Close()
+
+	for _, file := range files {
+		if err := addFileToZip(w, file, filepath.Base(file)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addFileToZip 把 srcFile 以 nameInZip 为名写入 zip.Writer, 保留文件权限信息。
+func addFileToZip(w *zip.Writer, srcFile, nameInZip string) error {
+	info, err := os.Stat(srcFile)
+	if err != nil {
+		return err
+	}
+	header, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return err
+	}
+	header.Name = filepath.ToSlash(nameInZip)
+	header.Method = zip.Deflate
+
+	writer, err := w.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+
+	src, err := os.Open(srcFile)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	_, err = io.Copy(writer, src)
+	return err
+}
+
+// Unzip 把 srcZip 解压到 destDir, 拒绝解压路径逃逸到 destDir 之外的条目（zip-slip 防御）。
+func Unzip(srcZip, destDir string) error {
+	r, err := zip.OpenReader(srcZip)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	for _, f := range r.File {
+		path, err := safeJoin(destDir, f.Name)
+		if err != nil {
+			return err
+		}
+
+		if f.FileInfo().IsDir() {
+			if err := os.MkdirAll(path, 0700); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			return err
+		}
+		if err := extractZipFile(f, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// extractZipFile 把 zip 中的单个文件 f 写入 destPath。
+func extractZipFile(f *zip.File, destPath string) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	return goutil.CreateFile(destPath, rc)
+}
+
+// TarGzDir 把 srcDir 文件夹的全部内容打包为 destTgz, 保留相对路径与文件权限。
+func TarGzDir(srcDir, destTgz string) error {
+	_, out, err := goutil.CreateReturnFile(destTgz, strings.NewReader(""))
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	gw := gzip.NewWriter(out)
+	defer gw.Close()
+
+	tw := tar.NewWriter(gw)
+	defer tw.Close()
+
+	files, err := walkFiles(srcDir)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		rel, err := filepath.Rel(srcDir, file)
+		if err != nil {
+			return err
+		}
+		if err := addFileToTar(tw, file, rel); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addFileToTar 把 srcFile 以 nameInTar 为名写入 tar.Writer, 保留文件权限信息。
+func addFileToTar(tw *tar.Writer, srcFile, nameInTar string) error {
+	info, err := os.Stat(srcFile)
+	if err != nil {
+		return err
+	}
+	header, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	header.Name = filepath.ToSlash(nameInTar)
+
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+
+	src, err := os.Open(srcFile)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	_, err = io.Copy(tw, src)
+	return err
+}
+
+// UntarGz 把 srcTgz 解压到 destDir, 拒绝解压路径逃逸到 destDir 之外的条目（zip-slip 防御）。
+func UntarGz(srcTgz, destDir string) error {
+	in, err := os.Open(srcTgz)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	gr, err := gzip.NewReader(in)
+	if err != nil {
+		return err
+	}
+	defer gr.Close()
+
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		path, err := safeJoin(destDir, header.Name)
+		if err != nil {
+			return err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(path, 0700); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+				return err
+			}
+			if err := goutil.CreateFile(path, tr); err != nil {
+				return err
+			}
+		default:
+			// 忽略符号链接等其他条目类型
+		}
+	}
+}
+
+// safeJoin 把 name 拼接到 destDir 下, 如果结果逃逸到 destDir 之外则返回错误（zip-slip 防御）。
+func safeJoin(destDir, name string) (string, error) {
+	path := filepath.Join(destDir, name)
+	if !strings.HasPrefix(path, filepath.Clean(destDir)+string(os.PathSeparator)) && path != filepath.Clean(destDir) {
+		return "", fmt.Errorf("illegal file path in archive: %s", name)
+	}
+	return path, nil
+}
+
+// walkFiles 返回 dir 下全部普通文件的路径（不含子文件夹自身）。
+func walkFiles(dir string) (files []string, err error) {
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		files = append(files, path)
+		return nil
+	})
+	if err == nil && len(files) == 0 {
+		return nil, errors.New("no files found in " + dir)
+	}
+	return files, err
+}
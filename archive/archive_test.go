@@ -0,0 +1,106 @@
+package archive
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSafeJoinRejectsPathEscape(t *testing.T) {
+	destDir := t.TempDir()
+
+	for _, name := range []string{
+		"../escape.txt",
+		"a/../../escape.txt",
+	} {
+		if _, err := safeJoin(destDir, name); err == nil {
+			t.Fatalf("safeJoin(%q) should reject a path escaping destDir", name)
+		}
+	}
+
+	for _, name := range []string{
+		"ok.txt",
+		"sub/ok.txt",
+	} {
+		path, err := safeJoin(destDir, name)
+		if err != nil {
+			t.Fatalf("safeJoin(%q) should be allowed: %v", name, err)
+		}
+		if filepath.Dir(path) != destDir && filepath.Dir(filepath.Dir(path)) != destDir {
+			t.Fatalf("safeJoin(%q) = %q, not under destDir %q", name, path, destDir)
+		}
+	}
+}
+
+// TestUnzipRejectsZipSlip builds a zip whose entry name tries to escape destDir
+// via "../" and checks Unzip refuses it instead of writing outside destDir.
+func TestUnzipRejectsZipSlip(t *testing.T) {
+	srcZip := filepath.Join(t.TempDir(), "evil.zip")
+	f, err := os.Create(srcZip)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := zip.NewWriter(f)
+	entry, err := w.Create("../../tmp/evil.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := entry.Write([]byte("pwned")); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	destDir := t.TempDir()
+	if err := Unzip(srcZip, destDir); err == nil {
+		t.Fatal("Unzip should reject a zip-slip entry")
+	}
+
+	escaped := filepath.Join(filepath.Dir(filepath.Dir(destDir)), "tmp", "evil.txt")
+	if _, err := os.Stat(escaped); err == nil {
+		os.Remove(escaped)
+		t.Fatalf("Unzip must not write outside destDir, but found %s", escaped)
+	}
+}
+
+func TestZipDirUnzipRoundTripPreservesRelativePaths(t *testing.T) {
+	srcDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(srcDir, "sub"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(srcDir, "a.txt"), []byte("a"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(srcDir, "sub", "a.txt"), []byte("sub-a"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	destZip := filepath.Join(t.TempDir(), "out.zip")
+	if err := ZipDir(srcDir, destZip); err != nil {
+		t.Fatal(err)
+	}
+
+	destDir := t.TempDir()
+	if err := Unzip(destZip, destDir); err != nil {
+		t.Fatal(err)
+	}
+
+	top, err := os.ReadFile(filepath.Join(destDir, "a.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(top) != "a" {
+		t.Fatalf("top-level a.txt = %q, want %q", top, "a")
+	}
+
+	sub, err := os.ReadFile(filepath.Join(destDir, "sub", "a.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(sub) != "sub-a" {
+		t.Fatalf("sub/a.txt = %q, want %q", sub, "sub-a")
+	}
+}
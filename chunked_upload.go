@@ -0,0 +1,154 @@
+package goutil
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ChunkState 描述一个分片上传请求的处理结果。
+type ChunkState struct {
+	Hash  string // 整个文件的 checksum, 同时也是临时文件夹名
+	Index int    // 当前分片的序号, 从 0 开始
+	Total int    // 分片总数
+	Dir   string // 存放分片的临时文件夹, 即 tempDir/Hash
+}
+
+// GetChunkedFile 从 r 中读取一个分片并写入 tempDir/hash/hash-index,
+// 用于支持浏览器端的大文件分片（断点续传）上传。
+// 表单字段要求: hash(整个文件的 checksum), index(分片序号), total(分片总数), file(分片内容)。
+func GetChunkedFile(r *http.Request, tempDir string) (*ChunkState, error) {
+	hash := r.FormValue("hash")
+	if hash == "" {
+		return nil, errors.New("hash is empty")
+	}
+	index, err := strconv.Atoi(r.FormValue("index"))
+	if err != nil {
+		return nil, err
+	}
+	total, err := strconv.Atoi(r.FormValue("total"))
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Join(tempDir, hash)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	chunkPath := filepath.Join(dir, chunkName(hash, index))
+	if err := CreateFile(chunkPath, file); err != nil {
+		return nil, err
+	}
+
+	return &ChunkState{Hash: hash, Index: index, Total: total, Dir: dir}, nil
+}
+
+// ListChunkIndices 返回 tempDir/hash 中已经上传完成的分片序号, 供客户端断点续传时核对。
+func ListChunkIndices(tempDir, hash string) ([]int, error) {
+	dir := filepath.Join(tempDir, hash)
+	if PathIsNotExist(dir) {
+		return nil, nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := hash + "-"
+	var indices []int
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		i, err := strconv.Atoi(strings.TrimPrefix(name, prefix))
+		if err != nil {
+			continue
+		}
+		indices = append(indices, i)
+	}
+	sort.Ints(indices)
+	return indices, nil
+}
+
+// AssembleChunks 把 tempDir/hash 中的所有分片按 index 数字顺序拼接成 finalPath,
+// 重新计算 Sha256Hex 并与 hash 比对，通过后删除临时文件夹。
+// 如果 finalPath 已存在则视为已完成，直接返回 nil（支持客户端重复提交 finalize 请求）。
+// 拼接先写入 finalPath 同目录下的临时文件, 校验通过后才 rename 到 finalPath,
+// 这样任何失败（拼接出错或校验不匹配）都不会在 finalPath 留下不完整的文件，
+// 临时文件夹也会被保留以便客户端重试。
+func AssembleChunks(tempDir, hash, finalPath string) error {
+	if PathIsExist(finalPath) {
+		return nil
+	}
+
+	dir := filepath.Join(tempDir, hash)
+	indices, err := ListChunkIndices(tempDir, hash)
+	if err != nil {
+		return err
+	}
+	if len(indices) == 0 {
+		return fmt.Errorf("no chunks found in %s", dir)
+	}
+
+	tmpPath := finalPath + ".part-" + hash
+	if err := assembleTo(tmpPath, dir, hash, indices); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	if err := os.Rename(tmpPath, finalPath); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return os.RemoveAll(dir)
+}
+
+// assembleTo 把 dir 中按 indices 顺序排列的分片拼接写入 tmpPath,
+// 重新计算 Sha256Hex 并与 hash 比对。
+func assembleTo(tmpPath, dir, hash string, indices []int) error {
+	out, err := os.OpenFile(tmpPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	hasher := sha256.New()
+	for _, i := range indices {
+		chunkPath := filepath.Join(dir, chunkName(hash, i))
+		chunk, err := os.Open(chunkPath)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(io.MultiWriter(out, hasher), chunk)
+		chunk.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	if sum := hex.EncodeToString(hasher.Sum(nil)); sum != hash {
+		return fmt.Errorf("checksums do not match: want %s, got %s", hash, sum)
+	}
+	return nil
+}
+
+// chunkName 返回分片文件名, 格式为 hash-index。
+func chunkName(hash string, index int) string {
+	return fmt.Sprintf("%s-%d", hash, index)
+}
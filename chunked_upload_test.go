@@ -0,0 +1,108 @@
+package goutil
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+// newChunkRequest builds a multipart POST request carrying one chunk.
+func newChunkRequest(t *testing.T, hash string, index, total int, data []byte) *http.Request {
+	t.Helper()
+	body := new(bytes.Buffer)
+	w := multipart.NewWriter(body)
+	for key, value := range map[string]string{
+		"hash":  hash,
+		"index": strconv.Itoa(index),
+		"total": strconv.Itoa(total),
+	} {
+		if err := w.WriteField(key, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	part, err := w.CreateFormFile("file", "chunk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := part.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/upload", body)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	return req
+}
+
+func TestAssembleChunks(t *testing.T) {
+	tempDir := t.TempDir()
+	chunk0 := []byte("hello, ")
+	chunk1 := []byte("world!")
+	whole := append(append([]byte{}, chunk0...), chunk1...)
+	hash := Sha256Hex(whole)
+
+	if _, err := GetChunkedFile(newChunkRequest(t, hash, 0, 2, chunk0), tempDir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := GetChunkedFile(newChunkRequest(t, hash, 1, 2, chunk1), tempDir); err != nil {
+		t.Fatal(err)
+	}
+
+	indices, err := ListChunkIndices(tempDir, hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(indices) != 2 || indices[0] != 0 || indices[1] != 1 {
+		t.Fatalf("unexpected indices: %v", indices)
+	}
+
+	finalPath := filepath.Join(tempDir, "final")
+	if err := AssembleChunks(tempDir, hash, finalPath); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := os.ReadFile(finalPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, whole) {
+		t.Fatalf("assembled file mismatch: got %q, want %q", got, whole)
+	}
+
+	// Calling AssembleChunks again once finalPath already exists must be a no-op.
+	if err := AssembleChunks(tempDir, hash, finalPath); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAssembleChunksFailureLeavesNoPartialFile(t *testing.T) {
+	tempDir := t.TempDir()
+	hash := Sha256Hex([]byte("the real content"))
+
+	if _, err := GetChunkedFile(newChunkRequest(t, hash, 0, 1, []byte("the real content")), tempDir); err != nil {
+		t.Fatal(err)
+	}
+
+	finalPath := filepath.Join(tempDir, "final")
+	wrongHash := Sha256Hex([]byte("something else"))
+	dir := filepath.Join(tempDir, hash)
+
+	// Assembling under the wrong hash can't find the chunk directory and must fail,
+	// leaving no partial finalPath behind and keeping the real temp dir for retry.
+	if err := AssembleChunks(tempDir, wrongHash, finalPath); err == nil {
+		t.Fatal("expected an error when the chunk directory does not match hash")
+	}
+	if PathIsExist(finalPath) {
+		t.Fatal("finalPath must not exist after a failed assembly")
+	}
+	if PathIsNotExist(dir) {
+		t.Fatal("temp dir must be kept after a failed assembly so the client can retry")
+	}
+}
@@ -0,0 +1,142 @@
+// Package config 提供统一的配置文件读取，支持 YAML/TOML/INI/JSON 四种格式，
+// 并可选地用环境变量覆盖其中的字段。
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/ahui2016/goutil"
+	"gopkg.in/ini.v1"
+	"gopkg.in/yaml.v3"
+)
+
+// Load 根据 path 的扩展名（.yaml/.yml, .toml, .ini, .json）解码配置文件到 v。
+func Load(path string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		return yaml.Unmarshal(data, v)
+	case ".toml":
+		return toml.Unmarshal(data, v)
+	case ".ini":
+		return loadIni(data, v)
+	case ".json":
+		return json.Unmarshal(data, v)
+	default:
+		return fmt.Errorf("config: unsupported file extension %q", ext)
+	}
+}
+
+// loadIni 把 data 解析为 ini 并映射到 v。
+func loadIni(data []byte, v interface{}) error {
+	file, err := ini.Load(data)
+	if err != nil {
+		return err
+	}
+	return file.MapTo(v)
+}
+
+// LoadWithEnv 先用 Load 解码 path, 然后用形如 envPrefix_FIELD 的环境变量
+// 覆盖 v 中同名的字段（支持用 "_" 分隔访问嵌套结构体字段,
+// 切片字段用逗号分隔的环境变量值覆盖）。
+func LoadWithEnv(path, envPrefix string, v interface{}) error {
+	if err := Load(path, v); err != nil {
+		return err
+	}
+	return overlayEnv(reflect.ValueOf(v).Elem(), envPrefix)
+}
+
+// overlayEnv 递归遍历 val 的每个字段，如果存在名为 prefix_FIELD 的环境变量则覆盖该字段。
+func overlayEnv(val reflect.Value, prefix string) error {
+	t := val.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fieldVal := val.Field(i)
+		if !fieldVal.CanSet() {
+			continue
+		}
+
+		name := prefix + "_" + strings.ToUpper(field.Name)
+
+		if fieldVal.Kind() == reflect.Struct {
+			if err := overlayEnv(fieldVal, name); err != nil {
+				return err
+			}
+			continue
+		}
+
+		raw, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		if err := setFieldFromEnv(fieldVal, raw); err != nil {
+			return fmt.Errorf("config: env %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// setFieldFromEnv 把环境变量的字符串值 raw 转换后写入 fieldVal。
+func setFieldFromEnv(fieldVal reflect.Value, raw string) error {
+	switch fieldVal.Kind() {
+	case reflect.String:
+		fieldVal.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		fieldVal.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return err
+		}
+		fieldVal.SetInt(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return err
+		}
+		fieldVal.SetFloat(f)
+	case reflect.Slice:
+		parts := strings.Split(raw, ",")
+		slice := reflect.MakeSlice(fieldVal.Type(), len(parts), len(parts))
+		for i, part := range parts {
+			if err := setFieldFromEnv(slice.Index(i), strings.TrimSpace(part)); err != nil {
+				return err
+			}
+		}
+		fieldVal.Set(slice)
+	default:
+		return fmt.Errorf("unsupported field kind %s", fieldVal.Kind())
+	}
+	return nil
+}
+
+// MustLoad 与 Load 相同，但解码失败时直接 panic（与 goutil.CheckErrorPanic 风格一致）。
+func MustLoad(path string, v interface{}) {
+	goutil.CheckErrorPanic(Load(path, v))
+}
+
+// GetProjectPath 返回当前工作目录（正斜杠形式），
+// 如果工作目录以 "/test" 结尾（测试从子文件夹运行时），会去掉该后缀以得到项目根目录。
+func GetProjectPath() (string, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	wd = filepath.ToSlash(wd)
+	return strings.TrimSuffix(wd, "/test"), nil
+}
@@ -137,6 +137,9 @@ func JsonMessage(w http.ResponseWriter, message string, code int) {
 }
 
 // JsonResponse 要用于向前端返回有用数据。
+// JsonResponse 本身不做压缩或缓存校验，只管把 obj 写给 w；
+// httpx.GzipHandler 与 httpx.ETagHandler 是通过包一层 http.ResponseWriter 来工作的，
+// 所以挂载它们即可让 JsonResponse 的输出自动获得压缩与 ETag 支持，无需改动这里的代码。
 // 参考 https://stackoverflow.com/questions/59763852/can-you-return-json-in-golang-http-error
 func JsonResponse(w http.ResponseWriter, obj interface{}, code int) {
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
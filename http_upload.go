@@ -0,0 +1,127 @@
+package goutil
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// HttpPostMultipart 以 multipart/form-data 格式向 url 发送 fields 与 files,
+// 并自动为每个文件计算 checksum 字段（与 GetFileContents 的校验方式一致）。
+// 适用于向使用 GetFileContents 接收文件的服务端上传。
+func HttpPostMultipart(
+	url string, fields map[string]string, files map[string]io.Reader, cookies []*http.Cookie,
+) (*http.Response, error) {
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+
+	if err := writeMultipartFields(writer, fields, files); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+	return http.DefaultClient.Do(req)
+}
+
+// HttpPostMultipartStream 与 HttpPostMultipart 功能相同，
+// 但使用 io.Pipe 边写边传，避免把大文件一次性读入内存。
+func HttpPostMultipartStream(
+	url string, fields map[string]string, files map[string]io.Reader, cookies []*http.Cookie,
+) (*http.Response, error) {
+	pr, pw := io.Pipe()
+	writer := multipart.NewWriter(pw)
+
+	go func() {
+		err := writeMultipartFields(writer, fields, files)
+		if err == nil {
+			err = writer.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+
+	req, err := http.NewRequest(http.MethodPost, url, pr)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+	return http.DefaultClient.Do(req)
+}
+
+// writeMultipartFields 把 fields 写成普通表单字段，把 files 流式写成文件字段，
+// 一边复制一边用 sha256 计算 checksum（与 GetFileContents 的校验方式一致），
+// 在对应文件写完之后把 checksum 作为紧随其后的表单字段写出，
+// 这样每个文件都不需要先整体读入内存再发送。
+// 只有一个文件时 checksum 字段名固定为 "checksum", 与 GetFileContents 的约定一致；
+// 有多个文件时每个文件各自对应一个 "<name>_checksum" 字段，避免互相覆盖。
+func writeMultipartFields(
+	writer *multipart.Writer, fields map[string]string, files map[string]io.Reader,
+) error {
+	for key, value := range fields {
+		if err := writer.WriteField(key, value); err != nil {
+			return err
+		}
+	}
+
+	for name, r := range files {
+		part, err := writer.CreateFormFile(name, name)
+		if err != nil {
+			return err
+		}
+
+		hasher := sha256.New()
+		if _, err := io.Copy(part, io.TeeReader(r, hasher)); err != nil {
+			return err
+		}
+
+		checksum := hex.EncodeToString(hasher.Sum(nil))
+		if err := writer.WriteField(checksumFieldName(name, len(files)), checksum); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checksumFieldName 返回 name 对应文件的 checksum 字段名。
+func checksumFieldName(name string, fileCount int) string {
+	if fileCount <= 1 {
+		return "checksum"
+	}
+	return name + "_checksum"
+}
+
+// UploadFile 把本地文件 filePath 以 multipart/form-data 上传到 url,
+// 自动填充 filename、checksum 以及 extra 中的附加字段。
+func UploadFile(
+	url, filePath string, extra map[string]string, cookies []*http.Cookie,
+) (*http.Response, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	fields := map[string]string{"filename": filepath.Base(filePath)}
+	for k, v := range extra {
+		fields[k] = v
+	}
+	files := map[string]io.Reader{"file": file}
+	return HttpPostMultipart(url, fields, files, cookies)
+}
@@ -0,0 +1,210 @@
+// Package httpx 提供与 http.Handler 组合使用的中间件：gzip 压缩、ETag 缓存校验
+// 以及带预压缩缓存的静态文件服务。
+package httpx
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ahui2016/goutil"
+)
+
+// DefaultGzipLevel 是 GzipHandler 使用的默认压缩级别。
+const DefaultGzipLevel = gzip.DefaultCompression
+
+// GzipHandler 用 DefaultGzipLevel 包装 h, 如果客户端的 Accept-Encoding 包含 gzip,
+// 则透明地压缩响应体并设置 Content-Encoding/Vary 头。
+func GzipHandler(h http.Handler) http.Handler {
+	return GzipHandlerLevel(h, DefaultGzipLevel)
+}
+
+// GzipHandlerLevel 与 GzipHandler 相同，但可以指定压缩级别（见 compress/gzip 的常量）。
+func GzipHandlerLevel(h http.Handler, level int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		gw, err := gzip.NewWriterLevel(w, level)
+		if err != nil {
+			h.ServeHTTP(w, r)
+			return
+		}
+		defer gw.Close()
+
+		w.Header().Set("Content-Encoding", "gzip")
+		h.ServeHTTP(&gzipResponseWriter{ResponseWriter: w, gw: gw}, r)
+	})
+}
+
+// gzipResponseWriter 把写入的内容转发给内部的 gzip.Writer。
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	gw *gzip.Writer
+}
+
+func (w *gzipResponseWriter) Write(b []byte) (int, error) {
+	return w.gw.Write(b)
+}
+
+// ETagHandler 缓冲 h 的响应体，用 goutil.Sha256Hex 计算 ETag，
+// 如果请求的 If-None-Match 与之匹配则返回 304 Not Modified。
+func ETagHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		buf := &bytes.Buffer{}
+		rec := &etagResponseWriter{ResponseWriter: w, buf: buf, code: http.StatusOK}
+		h.ServeHTTP(rec, r)
+
+		etag := `"` + goutil.Sha256Hex(buf.Bytes()) + `"`
+		w.Header().Set("ETag", etag)
+
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		for k, v := range rec.Header() {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(rec.code)
+		w.Write(buf.Bytes())
+	})
+}
+
+// etagResponseWriter 先把响应体写入 buf, 不直接写给底层连接，
+// 以便 ETagHandler 在知道完整内容后再决定是返回 304 还是完整响应。
+type etagResponseWriter struct {
+	http.ResponseWriter
+	buf  *bytes.Buffer
+	code int
+}
+
+func (w *etagResponseWriter) WriteHeader(code int) {
+	w.code = code
+}
+
+func (w *etagResponseWriter) Write(b []byte) (int, error) {
+	return w.buf.Write(b)
+}
+
+// staticAsset 是 ServeStaticFS 为单个文件预先计算好的内容与 gzip 变体。
+type staticAsset struct {
+	name        string
+	modTime     int64
+	raw         []byte
+	gzipped     []byte
+	contentType string
+}
+
+// ServeStaticFS 用 http.ServeContent 提供 fsys 中的静态文件，
+// 并在启动时把每个文件的 gzip 变体预先压缩并缓存在内存中（类似于一次性压缩
+// 好 JS/CSS 后反复复用的做法），同时设置 Cache-Control: max-age=cacheSeconds。
+func ServeStaticFS(prefix string, fsys fs.FS, cacheSeconds int) (http.Handler, error) {
+	assets, err := buildStaticAssets(fsys)
+	if err != nil {
+		return nil, err
+	}
+	cacheControl := "max-age=" + strconv.Itoa(cacheSeconds)
+
+	return http.StripPrefix(prefix, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(r.URL.Path, "/")
+		asset, ok := assets[name]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Cache-Control", cacheControl)
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		// The gzipped bytes are not seekable by byte range, and http.ServeContent
+		// would otherwise sniff Content-Type off the gzip magic bytes. So the gzip
+		// variant is written directly, without Range support; only the raw bytes
+		// go through http.ServeContent to get its Range/If-Modified-Since handling.
+		if asset.gzipped != nil && strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			w.Header().Set("Content-Encoding", "gzip")
+			w.Header().Set("Content-Type", asset.contentType)
+			w.Write(asset.gzipped)
+			return
+		}
+
+		http.ServeContent(w, r, asset.name, time.Unix(asset.modTime, 0), bytes.NewReader(asset.raw))
+	})), nil
+}
+
+// buildStaticAssets 遍历 fsys 中的每个文件，读取内容并预先计算其 gzip 变体。
+func buildStaticAssets(fsys fs.FS) (map[string]*staticAsset, error) {
+	assets := make(map[string]*staticAsset)
+	var mu sync.Mutex
+
+	err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		f, err := fsys.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		raw, err := io.ReadAll(f)
+		if err != nil {
+			return err
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		gzipped, err := gzipBytes(raw)
+		if err != nil {
+			return err
+		}
+
+		contentType := mime.TypeByExtension(filepath.Ext(path))
+		if contentType == "" {
+			contentType = http.DetectContentType(raw)
+		}
+
+		mu.Lock()
+		assets[path] = &staticAsset{
+			name:        path,
+			modTime:     info.ModTime().Unix(),
+			raw:         raw,
+			gzipped:     gzipped,
+			contentType: contentType,
+		}
+		mu.Unlock()
+		return nil
+	})
+	return assets, err
+}
+
+// gzipBytes 返回 data 的 gzip 压缩结果。
+func gzipBytes(data []byte) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	gw := gzip.NewWriter(buf)
+	if _, err := gw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := gw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
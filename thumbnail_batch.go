@@ -0,0 +1,241 @@
+package goutil
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ExternalCompressor 描述一个可选的外部压缩服务（例如 TinyPNG 之类），
+// ThumbnailBatch 会把图片 POST 给 Endpoint 并用返回的内容替代本地压缩结果。
+// APIKeys 支持配置多个 key 以便某个 key 被拒绝（返回 401/403）时自动换用下一个；
+// 其他失败（包括限流）会继续用同一个 key 重试。
+type ExternalCompressor struct {
+	Endpoint   string
+	APIKeys    []string
+	MaxRetries int // 每个文件的最大重试次数, <=0 时默认为 3
+}
+
+// BatchOpts 是 ThumbnailBatch 的选项。
+type BatchOpts struct {
+	Workers            int // 并发协程数, <=0 时默认为 4
+	ExternalCompressor *ExternalCompressor
+	Progress           func(done, total int) // 每处理完一个文件调用一次
+}
+
+// BatchResult 是 ThumbnailBatch 的处理结果。
+type BatchResult struct {
+	Succeeded  []string
+	Failed     map[string]error
+	BytesSaved int64
+}
+
+// ThumbnailBatch 批量生成缩略图（或调用外部压缩服务），
+// inputs 中的每一项既可以是图片文件，也可以是文件夹（会自动展开其中的
+// .png/.jpg/.jpeg/.webp 文件），outDir 保存结果，文件名与原文件一致。
+func ThumbnailBatch(inputs []string, outDir string, opts BatchOpts) (BatchResult, error) {
+	if opts.Workers <= 0 {
+		opts.Workers = 4
+	}
+	MustMkdir(outDir)
+
+	files, err := expandBatchInputs(inputs)
+	if err != nil {
+		return BatchResult{}, err
+	}
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		result  = BatchResult{Failed: make(map[string]error)}
+		done    int
+		invalid = newInvalidKeySet()
+		jobs    = make(chan string)
+	)
+
+	for i := 0; i < opts.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for file := range jobs {
+				saved, err := thumbnailOne(file, outDir, opts.ExternalCompressor, invalid)
+
+				mu.Lock()
+				if err != nil {
+					result.Failed[file] = err
+				} else {
+					result.Succeeded = append(result.Succeeded, file)
+					result.BytesSaved += saved
+				}
+				done++
+				if opts.Progress != nil {
+					opts.Progress(done, len(files))
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, file := range files {
+		jobs <- file
+	}
+	close(jobs)
+	wg.Wait()
+
+	return result, nil
+}
+
+// expandBatchInputs 把 inputs 中的文件夹展开为其中的图片文件, 文件则原样保留。
+func expandBatchInputs(inputs []string) ([]string, error) {
+	exts := []string{".png", ".jpg", ".jpeg", ".webp"}
+
+	var files []string
+	for _, input := range inputs {
+		if PathIsNotExist(input) {
+			return nil, fmt.Errorf("not found: %s", input)
+		}
+		info, err := os.Stat(input)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, input)
+			continue
+		}
+		for _, ext := range exts {
+			found, err := GetFilesByExt(input, ext)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, found...)
+		}
+	}
+	return files, nil
+}
+
+// thumbnailOne 处理单个文件, 返回节省的字节数。
+func thumbnailOne(file, outDir string, compressor *ExternalCompressor, invalid *invalidKeySet) (int64, error) {
+	img, err := ioutil.ReadFile(file)
+	if err != nil {
+		return 0, err
+	}
+
+	outPath := filepath.Join(outDir, filepath.Base(file))
+
+	if compressor == nil {
+		if err := BytesToThumb(img, outPath); err != nil {
+			return 0, err
+		}
+		info, err := os.Stat(outPath)
+		if err != nil {
+			return 0, err
+		}
+		return int64(len(img)) - info.Size(), nil
+	}
+
+	compressed, err := compressWithRetry(img, compressor, invalid)
+	if err != nil {
+		return 0, err
+	}
+	if err := CreateFile(outPath, bytes.NewReader(compressed)); err != nil {
+		return 0, err
+	}
+	return int64(len(img) - len(compressed)), nil
+}
+
+// compressWithRetry 把 img 发送给 compressor.Endpoint, 使用尚未失效的 key,
+// 失败时按指数退避重试，遇到鉴权失败则把该 key 标记为失效并换用下一个。
+func compressWithRetry(img []byte, compressor *ExternalCompressor, invalid *invalidKeySet) ([]byte, error) {
+	maxRetries := compressor.MaxRetries
+	if maxRetries <= 0 {
+		maxRetries = 3
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		key, ok := invalid.pickKey(compressor.APIKeys)
+		if !ok {
+			return nil, fmt.Errorf("no usable api key left for %s", compressor.Endpoint)
+		}
+
+		compressed, unauthorized, err := postToCompressor(compressor.Endpoint, key, img)
+		if err == nil {
+			return compressed, nil
+		}
+		if unauthorized {
+			invalid.markInvalid(key)
+		}
+		lastErr = err
+		time.Sleep(backoffDuration(attempt))
+	}
+	return nil, fmt.Errorf("compress failed after %d attempts: %w", maxRetries+1, lastErr)
+}
+
+// postToCompressor 把 img 直接作为请求体（application/octet-stream）POST 给 endpoint,
+// 并以 key 作为 Authorization 头。unauthorized 表示返回了 401/403, 调用方应停止使用该 key。
+func postToCompressor(endpoint, key string, img []byte) (compressed []byte, unauthorized bool, err error) {
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(img))
+	if err != nil {
+		return nil, false, err
+	}
+	req.Header.Set("Authorization", "Bearer "+key)
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return nil, true, fmt.Errorf("unauthorized: %s", endpoint)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, false, fmt.Errorf("compressor returned %d", resp.StatusCode)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, false, err
+	}
+	return body, false, nil
+}
+
+// backoffDuration 返回第 attempt 次重试前的等待时间（指数退避）。
+func backoffDuration(attempt int) time.Duration {
+	return time.Duration(1<<attempt) * 100 * time.Millisecond
+}
+
+// invalidKeySet 记录已失效（返回 401/403）的 api key, 供并发 worker 共享。
+type invalidKeySet struct {
+	mu   sync.Mutex
+	seen map[string]bool
+}
+
+func newInvalidKeySet() *invalidKeySet {
+	return &invalidKeySet{seen: make(map[string]bool)}
+}
+
+// pickKey 返回 keys 中第一个尚未失效的 key。
+func (s *invalidKeySet) pickKey(keys []string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, k := range keys {
+		if !s.seen[k] {
+			return k, true
+		}
+	}
+	return "", false
+}
+
+// markInvalid 把 key 标记为失效, 之后的 worker 不再使用它。
+func (s *invalidKeySet) markInvalid(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seen[key] = true
+}